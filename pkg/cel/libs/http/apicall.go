@@ -0,0 +1,70 @@
+package http
+
+import "fmt"
+
+// APICallConfig is the plain-data config surface this package exposes for
+// building a ContextInterface from an APICall spec. The APICall CRD type
+// and the controller call site that populate an APICallConfig from cluster
+// objects (e.g. resolving a Secret reference into TLS.ClientCertPEM) live
+// outside this package; NewHTTPForAPICall is the entry point they call into
+// once that resolution is done.
+type APICallConfig struct {
+	// TLS configures the client transport, including an optional mTLS
+	// keypair for calling mutual-TLS-protected endpoints. A zero value
+	// keeps the client passed to NewHTTPForAPICall, with no custom TLS
+	// settings.
+	TLS ClientConfig
+
+	// Auth selects and configures the Authenticator attached to every
+	// outgoing request. A zero value (Type == "") leaves requests
+	// unauthenticated.
+	Auth AuthConfig
+
+	// Cache configures response caching and single-flight de-duplication.
+	// A zero TTL (the default) leaves caching disabled.
+	Cache CacheConfig
+
+	// Protection configures the per-host circuit breaker and rate
+	// limiter. A nil value falls back to the config set by
+	// SetDefaultHostProtection, if any; an explicit zero value disables
+	// host protection for this APICall even if a global default is set.
+	Protection *HostProtectionConfig
+}
+
+// NewHTTPForAPICall builds a ContextInterface for a single APICall: it
+// resolves cfg.Auth into an Authenticator, applies it, cfg.Cache, and
+// cfg.Protection (or the global default) alongside opts, and finally
+// applies cfg.TLS, since ClientWithTLS replaces the underlying client while
+// carrying over the options already applied.
+func NewHTTPForAPICall(client ClientInterface, cfg APICallConfig, opts ...Option) (ContextInterface, error) {
+	auth, err := BuildAuthenticator(cfg.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build authenticator for APICall: %w", err)
+	}
+	if auth != nil {
+		opts = append(opts, WithAuthenticator(auth))
+	}
+	if cfg.Cache.TTL > 0 {
+		opts = append(opts, WithCache(cfg.Cache))
+	}
+
+	protection := cfg.Protection
+	if protection == nil {
+		if global, ok := defaultHostProtection(); ok {
+			protection = &global
+		}
+	}
+	if protection != nil && *protection != (HostProtectionConfig{}) {
+		opts = append(opts, WithHostProtection(*protection))
+	}
+
+	base := NewHTTP(client, opts...)
+	if cfg.TLS == (ClientConfig{}) {
+		return base, nil
+	}
+	impl, ok := base.(*contextImpl)
+	if !ok {
+		return nil, fmt.Errorf("APICall TLS config requires a *contextImpl, got %T", base)
+	}
+	return impl.ClientWithTLS(cfg.TLS)
+}
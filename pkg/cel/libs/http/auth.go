@@ -0,0 +1,239 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authenticator attaches credentials to an outgoing request. Implementations
+// must not log the credential they apply.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// BearerAuthenticator sets a static "Authorization: Bearer <token>" header.
+type BearerAuthenticator struct {
+	Token string
+}
+
+func (a *BearerAuthenticator) Authenticate(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// BasicAuthenticator sets HTTP Basic authentication credentials.
+type BasicAuthenticator struct {
+	Username string
+	Password string
+}
+
+func (a *BasicAuthenticator) Authenticate(_ context.Context, req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// cachingTokenSource caches the result of fetch and refreshes it skew
+// before the cached token's expiry, so a request started just before
+// expiry never gets handed a token that expires mid-flight.
+type cachingTokenSource struct {
+	mu     sync.Mutex
+	fetch  func(ctx context.Context) (token string, expiresIn time.Duration, err error)
+	token  string
+	expiry time.Time
+	skew   time.Duration
+}
+
+func newCachingTokenSource(fetch func(ctx context.Context) (string, time.Duration, error)) *cachingTokenSource {
+	return &cachingTokenSource{
+		fetch: fetch,
+		skew:  30 * time.Second,
+	}
+}
+
+func (c *cachingTokenSource) getToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Add(c.skew).Before(c.expiry) {
+		return c.token, nil
+	}
+	token, expiresIn, err := c.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = token
+	c.expiry = time.Now().Add(expiresIn)
+	return c.token, nil
+}
+
+// OAuth2ClientCredentialsAuthenticator obtains and caches a bearer token via
+// the OAuth2 client-credentials grant, refreshing it shortly before expiry.
+type OAuth2ClientCredentialsAuthenticator struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// Client performs the token request. Defaults to http.DefaultClient.
+	Client ClientInterface
+
+	source *cachingTokenSource
+	once   sync.Once
+}
+
+func (a *OAuth2ClientCredentialsAuthenticator) Authenticate(ctx context.Context, req *http.Request) error {
+	a.once.Do(func() {
+		a.source = newCachingTokenSource(a.fetchToken)
+	})
+	token, err := a.source.getToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to obtain OAuth2 token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a *OAuth2ClientCredentialsAuthenticator) fetchToken(ctx context.Context) (string, time.Duration, error) {
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	form := "grant_type=client_credentials"
+	if len(a.Scopes) > 0 {
+		form += "&scope=" + strings.Join(a.Scopes, "+")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.TokenURL, strings.NewReader(form))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(a.ClientID, a.ClientSecret)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", 0, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+	if tokenResp.ExpiresIn <= 0 {
+		tokenResp.ExpiresIn = 300
+	}
+	return tokenResp.AccessToken, time.Duration(tokenResp.ExpiresIn) * time.Second, nil
+}
+
+// defaultServiceAccountTokenPath is where the kubelet projects the
+// ServiceAccount token for an in-cluster pod.
+const defaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// ServiceAccountAuthenticator authenticates using the pod's projected
+// Kubernetes ServiceAccount token, re-reading it from disk once
+// RefreshInterval has passed since the last read so token rotation is
+// picked up.
+type ServiceAccountAuthenticator struct {
+	// TokenPath defaults to defaultServiceAccountTokenPath.
+	TokenPath string
+	// RefreshInterval controls how often the token is re-read from disk.
+	// Defaults to 1 minute, matching how often the kubelet rotates it.
+	RefreshInterval time.Duration
+
+	mu     sync.Mutex
+	token  string
+	readAt time.Time
+}
+
+func (a *ServiceAccountAuthenticator) Authenticate(_ context.Context, req *http.Request) error {
+	token, err := a.currentToken()
+	if err != nil {
+		return fmt.Errorf("failed to read service account token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a *ServiceAccountAuthenticator) currentToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	refresh := a.RefreshInterval
+	if refresh <= 0 {
+		refresh = time.Minute
+	}
+	if a.token != "" && time.Since(a.readAt) < refresh {
+		return a.token, nil
+	}
+
+	path := a.TokenPath
+	if path == "" {
+		path = defaultServiceAccountTokenPath
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	a.token = strings.TrimSpace(string(data))
+	a.readAt = time.Now()
+	return a.token, nil
+}
+
+// AuthConfig mirrors the `auth` field the APICall CRD (outside this package)
+// exposes to select one authentication method for outgoing requests. Exactly
+// one of Bearer/Basic/OAuth2/ServiceAccount should be set, matching Type.
+type AuthConfig struct {
+	// Type selects which authenticator BuildAuthenticator constructs: "",
+	// "Bearer", "Basic", "OAuth2ClientCredentials", or "ServiceAccount".
+	Type string
+
+	Bearer         *BearerAuthenticator
+	Basic          *BasicAuthenticator
+	OAuth2         *OAuth2ClientCredentialsAuthenticator
+	ServiceAccount *ServiceAccountAuthenticator
+}
+
+// BuildAuthenticator turns an AuthConfig into the Authenticator WithAuthenticator
+// expects, so an APICall controller can translate its resolved `auth` field
+// (e.g. Bearer/Basic credentials read from a Secret) into a real
+// contextImpl. An empty/unset Type returns a nil Authenticator, meaning no auth.
+func BuildAuthenticator(cfg AuthConfig) (Authenticator, error) {
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case "Bearer":
+		if cfg.Bearer == nil {
+			return nil, fmt.Errorf("auth type Bearer requires bearer config")
+		}
+		return cfg.Bearer, nil
+	case "Basic":
+		if cfg.Basic == nil {
+			return nil, fmt.Errorf("auth type Basic requires basic config")
+		}
+		return cfg.Basic, nil
+	case "OAuth2ClientCredentials":
+		if cfg.OAuth2 == nil {
+			return nil, fmt.Errorf("auth type OAuth2ClientCredentials requires oauth2 config")
+		}
+		return cfg.OAuth2, nil
+	case "ServiceAccount":
+		if cfg.ServiceAccount == nil {
+			return &ServiceAccountAuthenticator{}, nil
+		}
+		return cfg.ServiceAccount, nil
+	default:
+		return nil, fmt.Errorf("unknown auth type %q", cfg.Type)
+	}
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBearerAuthenticator(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	auth := &BearerAuthenticator{Token: "secret-token"}
+	if err := auth.Authenticate(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer secret-token" {
+		t.Fatalf("expected Bearer header, got %q", got)
+	}
+}
+
+func TestBasicAuthenticator(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	auth := &BasicAuthenticator{Username: "alice", Password: "hunter2"}
+	if err := auth.Authenticate(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user, pass, ok := req.BasicAuth()
+	if !ok || user != "alice" || pass != "hunter2" {
+		t.Fatalf("expected basic auth alice/hunter2, got %q/%q (ok=%v)", user, pass, ok)
+	}
+}
+
+func TestCachingTokenSourceReusesTokenUntilExpiry(t *testing.T) {
+	calls := 0
+	source := &cachingTokenSource{
+		skew: time.Millisecond,
+		fetch: func(ctx context.Context) (string, time.Duration, error) {
+			calls++
+			return "token", 50 * time.Millisecond, nil
+		},
+	}
+
+	first, err := source.getToken(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := source.getToken(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != "token" || second != "token" {
+		t.Fatalf("expected cached token, got %q then %q", first, second)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single fetch while the token is still fresh, got %d", calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := source.getToken(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a refresh after expiry, got %d calls", calls)
+	}
+}
+
+func TestOAuth2ClientCredentialsAuthenticatorSetsBearerHeader(t *testing.T) {
+	client := &fakeTokenClient{body: `{"access_token":"abc123","expires_in":3600}`, statusCode: http.StatusOK}
+	auth := &OAuth2ClientCredentialsAuthenticator{
+		TokenURL:     "http://token.invalid",
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Client:       client,
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	if err := auth.Authenticate(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer abc123" {
+		t.Fatalf("expected Bearer abc123, got %q", got)
+	}
+
+	// A second request within the token's lifetime shouldn't hit the token
+	// endpoint again.
+	req2, _ := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	if err := auth.Authenticate(context.Background(), req2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.calls != 1 {
+		t.Fatalf("expected the token to be cached, got %d token requests", client.calls)
+	}
+}
+
+type fakeTokenClient struct {
+	body       string
+	statusCode int
+	calls      int
+}
+
+func (c *fakeTokenClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return &http.Response{
+		StatusCode: c.statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(c.body)),
+	}, nil
+}
+
+func TestServiceAccountAuthenticatorReadsAndRotatesToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenPath, []byte("initial-token\n"), 0o600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	auth := &ServiceAccountAuthenticator{
+		TokenPath:       tokenPath,
+		RefreshInterval: 10 * time.Millisecond,
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	if err := auth.Authenticate(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer initial-token" {
+		t.Fatalf("expected initial token, got %q", got)
+	}
+
+	if err := os.WriteFile(tokenPath, []byte("rotated-token\n"), 0o600); err != nil {
+		t.Fatalf("failed to rewrite token file: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	req2, _ := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	if err := auth.Authenticate(context.Background(), req2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req2.Header.Get("Authorization"); got != "Bearer rotated-token" {
+		t.Fatalf("expected rotated token after refresh interval, got %q", got)
+	}
+}
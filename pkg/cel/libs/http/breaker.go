@@ -0,0 +1,253 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+)
+
+var (
+	breakerStateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kyverno",
+		Subsystem: "cel_http",
+		Name:      "circuit_breaker_state",
+		Help:      "Circuit breaker state per host (0=closed, 1=open, 2=half-open).",
+	}, []string{"host"})
+	breakerTripsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kyverno",
+		Subsystem: "cel_http",
+		Name:      "circuit_breaker_trips_total",
+		Help:      "Total number of times the circuit breaker opened for a host.",
+	}, []string{"host"})
+	rateLimitedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kyverno",
+		Subsystem: "cel_http",
+		Name:      "rate_limited_requests_total",
+		Help:      "Total number of requests rejected by the per-host rate limiter.",
+	}, []string{"host"})
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreakerConfig configures the per-host circuit breaker wrapped
+// around executeRequest by WithHostProtection.
+type CircuitBreakerConfig struct {
+	// FailureRatio is the fraction of failed requests, out of all requests
+	// counted since the breaker last changed state, that trips the breaker.
+	// E.g. 0.5 trips at 50% failures.
+	FailureRatio float64
+
+	// MinRequests is the minimum number of requests observed in the
+	// window before FailureRatio is evaluated, so a single early failure
+	// doesn't trip the breaker.
+	MinRequests int
+
+	// CooldownPeriod is how long the breaker stays open before allowing a
+	// single half-open trial request through.
+	CooldownPeriod time.Duration
+}
+
+// RateLimitConfig configures the per-host token-bucket rate limiter
+// wrapped around executeRequest by WithHostProtection.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the sustained request rate allowed per host.
+	RequestsPerSecond float64
+	// Burst is the maximum number of requests admitted instantaneously.
+	Burst int
+}
+
+// HostProtectionConfig bundles the circuit breaker and rate limiter applied
+// per upstream host.
+type HostProtectionConfig struct {
+	Breaker     CircuitBreakerConfig
+	RateLimiter RateLimitConfig
+}
+
+// hostGuard tracks the circuit breaker and rate limiter state for a single
+// host.
+type hostGuard struct {
+	mu sync.Mutex
+
+	cfg      CircuitBreakerConfig
+	state    breakerState
+	openedAt time.Time
+	// successes and failures count requests since the window started; the
+	// window resets whenever the breaker transitions back to closed.
+	successes int
+	failures  int
+
+	limiter *rate.Limiter
+}
+
+func newHostGuard(cfg HostProtectionConfig) *hostGuard {
+	g := &hostGuard{cfg: cfg.Breaker}
+	if cfg.RateLimiter.RequestsPerSecond > 0 {
+		g.limiter = rate.NewLimiter(rate.Limit(cfg.RateLimiter.RequestsPerSecond), cfg.RateLimiter.Burst)
+	}
+	return g
+}
+
+// allow reports whether a request to this host may proceed right now. It
+// waits on the rate limiter (respecting ctx cancellation) and evaluates the
+// breaker state, flipping open->half-open once the cooldown has elapsed.
+func (g *hostGuard) allow(ctx context.Context, host string) error {
+	if g.limiter != nil {
+		if err := g.limiter.Wait(ctx); err != nil {
+			rateLimitedTotal.WithLabelValues(host).Inc()
+			return fmt.Errorf("rate limited: %w", err)
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch g.state {
+	case breakerOpen:
+		if time.Since(g.openedAt) < g.cfg.CooldownPeriod {
+			return fmt.Errorf("circuit breaker open for host %s", host)
+		}
+		g.state = breakerHalfOpen
+		breakerStateGauge.WithLabelValues(host).Set(float64(breakerHalfOpen))
+		slog.Info("circuit breaker entering half-open", "host", host)
+	case breakerHalfOpen:
+		// Only one trial request is admitted at a time; reject the rest
+		// until the trial resolves.
+		return fmt.Errorf("circuit breaker half-open for host %s", host)
+	}
+	return nil
+}
+
+// recordResult updates the breaker's failure window with the outcome of a
+// request that was allowed through, tripping or resetting the breaker as
+// needed.
+func (g *hostGuard) recordResult(host string, success bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state == breakerHalfOpen {
+		if success {
+			g.state = breakerClosed
+			g.successes, g.failures = 0, 0
+			breakerStateGauge.WithLabelValues(host).Set(float64(breakerClosed))
+			slog.Info("circuit breaker closed after successful trial", "host", host)
+		} else {
+			g.openBreaker(host)
+		}
+		return
+	}
+
+	if success {
+		g.successes++
+	} else {
+		g.failures++
+	}
+
+	total := g.successes + g.failures
+	minRequests := g.cfg.MinRequests
+	if minRequests <= 0 {
+		minRequests = 1
+	}
+	if total < minRequests {
+		return
+	}
+	ratio := g.cfg.FailureRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+	if float64(g.failures)/float64(total) >= ratio {
+		g.openBreaker(host)
+	}
+}
+
+func (g *hostGuard) openBreaker(host string) {
+	g.state = breakerOpen
+	g.openedAt = time.Now()
+	g.successes, g.failures = 0, 0
+	breakerStateGauge.WithLabelValues(host).Set(float64(breakerOpen))
+	breakerTripsTotal.WithLabelValues(host).Inc()
+	slog.Warn("circuit breaker tripped", "host", host, "cooldown", g.cfg.CooldownPeriod)
+}
+
+// hostProtector hands out a hostGuard per distinct host, lazily created.
+type hostProtector struct {
+	cfg HostProtectionConfig
+
+	mu     sync.Mutex
+	guards map[string]*hostGuard
+}
+
+func newHostProtector(cfg HostProtectionConfig) *hostProtector {
+	return &hostProtector{
+		cfg:    cfg,
+		guards: make(map[string]*hostGuard),
+	}
+}
+
+func (p *hostProtector) guardFor(host string) *hostGuard {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.guards[host]
+	if !ok {
+		g = newHostGuard(p.cfg)
+		p.guards[host] = g
+	}
+	return g
+}
+
+// WithHostProtection wraps Get/Post with a per-host circuit breaker and
+// token-bucket rate limiter, so a misbehaving policy can't hammer an
+// external endpoint on every admission review.
+func WithHostProtection(cfg HostProtectionConfig) Option {
+	return func(c *contextImpl) {
+		c.protection = newHostProtector(cfg)
+	}
+}
+
+var (
+	defaultHostProtectionMu  sync.Mutex
+	defaultHostProtectionCfg *HostProtectionConfig
+)
+
+// SetDefaultHostProtection sets the HostProtectionConfig NewHTTPForAPICall
+// falls back to for any APICall that doesn't set its own Protection,
+// letting an operator enforce a baseline breaker/rate-limit policy across
+// all policies cluster-wide instead of requiring every APICall to set one.
+func SetDefaultHostProtection(cfg HostProtectionConfig) {
+	defaultHostProtectionMu.Lock()
+	defer defaultHostProtectionMu.Unlock()
+	defaultHostProtectionCfg = &cfg
+}
+
+// defaultHostProtection returns the config set by SetDefaultHostProtection,
+// if any.
+func defaultHostProtection() (HostProtectionConfig, bool) {
+	defaultHostProtectionMu.Lock()
+	defer defaultHostProtectionMu.Unlock()
+	if defaultHostProtectionCfg == nil {
+		return HostProtectionConfig{}, false
+	}
+	return *defaultHostProtectionCfg, true
+}
+
+// breakerRejectionResponse is the synthetic response returned when a
+// request is rejected by the circuit breaker or rate limiter, so policies
+// can distinguish it from a real upstream response and decide whether to
+// fail open or closed.
+func breakerRejectionResponse(err error) any {
+	return map[string]any{
+		"statusCode": 0,
+		"error":      err.Error(),
+	}
+}
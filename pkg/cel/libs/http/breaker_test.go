@@ -0,0 +1,147 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHostGuardTripsAfterFailureRatio(t *testing.T) {
+	guard := newHostGuard(HostProtectionConfig{
+		Breaker: CircuitBreakerConfig{
+			FailureRatio:   0.5,
+			MinRequests:    2,
+			CooldownPeriod: time.Hour,
+		},
+	})
+
+	if err := guard.allow(context.Background(), "example.com"); err != nil {
+		t.Fatalf("expected the breaker to start closed: %v", err)
+	}
+	guard.recordResult("example.com", false)
+	if guard.state != breakerClosed {
+		t.Fatalf("expected breaker to stay closed below MinRequests, got state %v", guard.state)
+	}
+
+	guard.recordResult("example.com", false)
+	if guard.state != breakerOpen {
+		t.Fatalf("expected breaker to trip once the failure ratio is reached, got state %v", guard.state)
+	}
+
+	if err := guard.allow(context.Background(), "example.com"); err == nil {
+		t.Fatalf("expected requests to be rejected while the breaker is open")
+	}
+}
+
+func TestHostGuardHalfOpenRecovery(t *testing.T) {
+	guard := newHostGuard(HostProtectionConfig{
+		Breaker: CircuitBreakerConfig{
+			FailureRatio:   0.5,
+			MinRequests:    1,
+			CooldownPeriod: 10 * time.Millisecond,
+		},
+	})
+
+	guard.openBreaker("example.com")
+	if err := guard.allow(context.Background(), "example.com"); err == nil {
+		t.Fatalf("expected rejection immediately after opening")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := guard.allow(context.Background(), "example.com"); err != nil {
+		t.Fatalf("expected a half-open trial to be admitted after cooldown: %v", err)
+	}
+	if guard.state != breakerHalfOpen {
+		t.Fatalf("expected state half-open, got %v", guard.state)
+	}
+
+	// A second concurrent request is rejected while the trial is pending.
+	if err := guard.allow(context.Background(), "example.com"); err == nil {
+		t.Fatalf("expected only one in-flight half-open trial to be admitted")
+	}
+
+	guard.recordResult("example.com", true)
+	if guard.state != breakerClosed {
+		t.Fatalf("expected breaker to close after a successful trial, got %v", guard.state)
+	}
+	if err := guard.allow(context.Background(), "example.com"); err != nil {
+		t.Fatalf("expected requests to be admitted once closed again: %v", err)
+	}
+}
+
+func TestHostGuardHalfOpenFailureReopens(t *testing.T) {
+	guard := newHostGuard(HostProtectionConfig{
+		Breaker: CircuitBreakerConfig{
+			FailureRatio:   0.5,
+			MinRequests:    1,
+			CooldownPeriod: 10 * time.Millisecond,
+		},
+	})
+
+	guard.openBreaker("example.com")
+	time.Sleep(20 * time.Millisecond)
+	if err := guard.allow(context.Background(), "example.com"); err != nil {
+		t.Fatalf("expected trial to be admitted: %v", err)
+	}
+	guard.recordResult("example.com", false)
+	if guard.state != breakerOpen {
+		t.Fatalf("expected breaker to reopen after a failed trial, got %v", guard.state)
+	}
+}
+
+func TestHostGuardRateLimiting(t *testing.T) {
+	guard := newHostGuard(HostProtectionConfig{
+		RateLimiter: RateLimitConfig{RequestsPerSecond: 0.1, Burst: 1},
+	})
+
+	if err := guard.allow(context.Background(), "example.com"); err != nil {
+		t.Fatalf("expected the first request within burst to be allowed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := guard.allow(ctx, "example.com"); err == nil {
+		t.Fatalf("expected the second request to be rate limited before the bucket refills")
+	}
+}
+
+// TestCacheDoesNotPersistBreakerRejection reproduces the scenario from the
+// code review: a request rejected by the circuit breaker must not poison
+// the cache for the rest of the TTL window, since a rejection is synthetic
+// (statusCode 0), never a real upstream response.
+func TestCacheDoesNotPersistBreakerRejection(t *testing.T) {
+	cache := newResponseCache(CacheConfig{TTL: time.Hour})
+
+	rejectionErr := errors.New("circuit breaker open for host example.com")
+	rejectionCalls := 0
+	rejectFetch := func() (any, http.Header, error) {
+		rejectionCalls++
+		return breakerRejectionResponse(rejectionErr), nil, nil
+	}
+	if _, err := cache.do("key", rejectFetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cache.do("key", rejectFetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rejectionCalls != 2 {
+		t.Fatalf("expected the rejection to never be cached, got %d calls", rejectionCalls)
+	}
+
+	realCalls := 0
+	realFetch := func() (any, http.Header, error) {
+		realCalls++
+		return map[string]any{"statusCode": http.StatusOK}, nil, nil
+	}
+	if _, err := cache.do("key", realFetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cache.do("key", realFetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if realCalls != 1 {
+		t.Fatalf("expected a real response to be cached normally, got %d calls", realCalls)
+	}
+}
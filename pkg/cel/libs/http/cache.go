@@ -0,0 +1,224 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	cacheHitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "kyverno",
+		Subsystem: "cel_http",
+		Name:      "cache_hits_total",
+		Help:      "Total number of HTTP responses served from cache.",
+	})
+	cacheMissesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "kyverno",
+		Subsystem: "cel_http",
+		Name:      "cache_misses_total",
+		Help:      "Total number of HTTP requests that missed the cache.",
+	})
+	cacheInflightRequests = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "kyverno",
+		Subsystem: "cel_http",
+		Name:      "cache_inflight_requests",
+		Help:      "Number of cache-miss requests currently in flight, including those collapsed by single-flight de-duplication.",
+	})
+)
+
+// CacheConfig configures the response cache wrapped around a contextImpl by
+// WithCache. A zero TTL disables caching even if CacheConfig is set.
+type CacheConfig struct {
+	// TTL is how long a response is cached when the upstream doesn't send
+	// its own freshness hints.
+	TTL time.Duration
+
+	// MaxEntries bounds the number of cached responses; the oldest entry
+	// is evicted once the limit is reached. 0 means unbounded.
+	MaxEntries int
+
+	// CacheableHosts allow-lists which request hosts may be cached. An
+	// empty list means all hosts are cacheable.
+	CacheableHosts []string
+
+	// HeaderKeys lists request header names that participate in the cache
+	// key, so responses that vary by e.g. Accept aren't confused.
+	HeaderKeys []string
+}
+
+type cacheEntry struct {
+	value     any
+	err       error
+	expiresAt time.Time
+}
+
+// responseCache de-duplicates concurrent requests for the same key via a
+// singleflight.Group, so only one of them reaches fetch, and caches the
+// result for a TTL so later callers skip fetch entirely until it expires.
+type responseCache struct {
+	cfg   CacheConfig
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	order   []string
+}
+
+func newResponseCache(cfg CacheConfig) *responseCache {
+	return &responseCache{
+		cfg:     cfg,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+func (c *responseCache) cacheable(host string) bool {
+	if len(c.cfg.CacheableHosts) == 0 {
+		return true
+	}
+	for _, h := range c.cfg.CacheableHosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *responseCache) get(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *responseCache) set(key string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; !exists {
+		c.order = append(c.order, key)
+		if c.cfg.MaxEntries > 0 && len(c.order) > c.cfg.MaxEntries {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[key] = entry
+}
+
+// do runs fetch, serving a cached result when available and collapsing
+// concurrent identical requests for the same key into a single call.
+func (c *responseCache) do(key string, fetch func() (any, http.Header, error)) (any, error) {
+	if entry, ok := c.get(key); ok {
+		cacheHitsTotal.Inc()
+		return entry.value, entry.err
+	}
+	cacheMissesTotal.Inc()
+
+	cacheInflightRequests.Inc()
+	defer cacheInflightRequests.Dec()
+
+	result, err, _ := c.group.Do(key, func() (any, error) {
+		value, headers, fetchErr := fetch()
+		ttl := c.cfg.TTL
+		if d, ok := freshnessTTL(headers); ok {
+			ttl = d
+		}
+		// statusCode 0 marks a synthetic rejection from the circuit
+		// breaker or rate limiter (see breakerRejectionResponse), never a
+		// real upstream response. Caching it would serve a stale "breaker
+		// open" sentinel for the rest of the TTL, even after the breaker
+		// recovers, and would stop the half-open probe from ever reaching
+		// the real endpoint.
+		if statusCode, ok := statusCodeOf(value); ok && statusCode == 0 {
+			ttl = 0
+		}
+		if fetchErr == nil && ttl > 0 {
+			c.set(key, cacheEntry{value: value, expiresAt: time.Now().Add(ttl)})
+		}
+		return value, fetchErr
+	})
+	return result, err
+}
+
+// freshnessTTL derives a TTL from Cache-Control: max-age or Expires, if the
+// response sets either.
+func freshnessTTL(headers http.Header) (time.Duration, bool) {
+	if headers == nil {
+		return 0, false
+	}
+	if cc := headers.Get("Cache-Control"); cc != "" {
+		for _, directive := range strings.Split(cc, ",") {
+			directive = strings.TrimSpace(directive)
+			if strings.HasPrefix(directive, "no-store") || strings.HasPrefix(directive, "no-cache") {
+				return 0, true
+			}
+			if rest, ok := strings.CutPrefix(directive, "max-age="); ok {
+				if seconds, err := strconv.Atoi(rest); err == nil && seconds >= 0 {
+					return time.Duration(seconds) * time.Second, true
+				}
+			}
+		}
+	}
+	if expires := headers.Get("Expires"); expires != "" {
+		if when, err := http.ParseTime(expires); err == nil {
+			if d := time.Until(when); d > 0 {
+				return d, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// cacheKey builds a stable key from the method, URL, a configured subset of
+// headers, and a hash of the body, so two requests are treated as the same
+// lookup only when all of those match.
+func cacheKey(method, url string, headers map[string]string, headerKeys []string, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, method)
+	io.WriteString(h, "\n")
+	io.WriteString(h, url)
+	io.WriteString(h, "\n")
+
+	keys := headerKeys
+	if len(keys) == 0 {
+		keys = make([]string, 0, len(headers))
+		for k := range headers {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		io.WriteString(h, strings.ToLower(k))
+		io.WriteString(h, "=")
+		io.WriteString(h, headers[k])
+		io.WriteString(h, "\n")
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// WithCache wraps Get/Post results in a TTL cache with single-flight
+// de-duplication, keyed on (method, url, a configured header subset, body
+// hash). Set cfg.TTL to 0 to leave caching disabled.
+func WithCache(cfg CacheConfig) Option {
+	return func(c *contextImpl) {
+		if cfg.TTL <= 0 {
+			return
+		}
+		c.cache = newResponseCache(cfg)
+	}
+}
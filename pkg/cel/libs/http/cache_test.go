@@ -0,0 +1,157 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheServesWithinTTL(t *testing.T) {
+	cache := newResponseCache(CacheConfig{TTL: time.Hour})
+	calls := 0
+	fetch := func() (any, http.Header, error) {
+		calls++
+		return "value", nil, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		result, err := cache.do("key", fetch)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "value" {
+			t.Fatalf("expected cached value, got %v", result)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected exactly one upstream fetch, got %d", calls)
+	}
+}
+
+func TestResponseCacheRefetchesAfterTTL(t *testing.T) {
+	cache := newResponseCache(CacheConfig{TTL: 10 * time.Millisecond})
+	calls := 0
+	fetch := func() (any, http.Header, error) {
+		calls++
+		return "value", nil, nil
+	}
+
+	if _, err := cache.do("key", fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := cache.do("key", fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a refetch once the TTL elapsed, got %d calls", calls)
+	}
+}
+
+func TestResponseCacheHonorsMaxAge(t *testing.T) {
+	cache := newResponseCache(CacheConfig{TTL: time.Hour})
+	calls := 0
+	fetch := func() (any, http.Header, error) {
+		calls++
+		return "value", http.Header{"Cache-Control": []string{"max-age=0"}}, nil
+	}
+
+	if _, err := cache.do("key", fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cache.do("key", fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected max-age=0 to prevent caching despite a 1h configured TTL, got %d calls", calls)
+	}
+}
+
+func TestResponseCacheHonorsNoStore(t *testing.T) {
+	cache := newResponseCache(CacheConfig{TTL: time.Hour})
+	calls := 0
+	fetch := func() (any, http.Header, error) {
+		calls++
+		return "value", http.Header{"Cache-Control": []string{"no-store"}}, nil
+	}
+
+	if _, err := cache.do("key", fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cache.do("key", fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected no-store to prevent caching, got %d calls", calls)
+	}
+}
+
+func TestResponseCacheEvictsOldestOnceOverCapacity(t *testing.T) {
+	cache := newResponseCache(CacheConfig{TTL: time.Hour, MaxEntries: 2})
+	cache.set("a", cacheEntry{value: "a", expiresAt: time.Now().Add(time.Hour)})
+	cache.set("b", cacheEntry{value: "b", expiresAt: time.Now().Add(time.Hour)})
+	cache.set("c", cacheEntry{value: "c", expiresAt: time.Now().Add(time.Hour)})
+
+	if _, ok := cache.get("a"); ok {
+		t.Fatalf("expected the oldest entry to have been evicted")
+	}
+	if _, ok := cache.get("b"); !ok {
+		t.Fatalf("expected entry b to remain cached")
+	}
+	if _, ok := cache.get("c"); !ok {
+		t.Fatalf("expected entry c to remain cached")
+	}
+}
+
+func TestResponseCacheCacheableHosts(t *testing.T) {
+	cache := newResponseCache(CacheConfig{TTL: time.Hour, CacheableHosts: []string{"allowed.example.com"}})
+	if !cache.cacheable("allowed.example.com") {
+		t.Fatalf("expected allow-listed host to be cacheable")
+	}
+	if cache.cacheable("other.example.com") {
+		t.Fatalf("expected non-allow-listed host to not be cacheable")
+	}
+
+	anyHostCache := newResponseCache(CacheConfig{TTL: time.Hour})
+	if !anyHostCache.cacheable("anything.example.com") {
+		t.Fatalf("expected an empty allow-list to permit any host")
+	}
+}
+
+func TestResponseCacheDeduplicatesConcurrentRequests(t *testing.T) {
+	cache := newResponseCache(CacheConfig{TTL: time.Hour})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	fetch := func() (any, http.Header, error) {
+		calls++
+		close(started)
+		<-release
+		return "value", nil, nil
+	}
+
+	results := make(chan any, 2)
+	go func() {
+		v, _ := cache.do("key", fetch)
+		results <- v
+	}()
+
+	<-started
+	go func() {
+		v, _ := cache.do("key", func() (any, http.Header, error) {
+			t.Error("second caller should have been collapsed by single-flight, not issued its own fetch")
+			return nil, nil, nil
+		})
+		results <- v
+	}()
+
+	close(release)
+	first := <-results
+	second := <-results
+	if first != "value" || second != "value" {
+		t.Fatalf("expected both callers to observe the single-flight result, got %v and %v", first, second)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single upstream fetch, got %d", calls)
+	}
+}
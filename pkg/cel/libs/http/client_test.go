@@ -0,0 +1,106 @@
+package http
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// generateSelfSignedCert returns a PEM-encoded self-signed certificate and
+// its PEM-encoded private key, suitable for exercising X509KeyPair parsing.
+func generateSelfSignedCert(t *testing.T) (certPEM, keyPEM string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	var certBuf, keyBuf bytes.Buffer
+	if err := pem.Encode(&certBuf, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		t.Fatalf("failed to encode certificate: %v", err)
+	}
+	if err := pem.Encode(&keyBuf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}); err != nil {
+		t.Fatalf("failed to encode key: %v", err)
+	}
+	return certBuf.String(), keyBuf.String()
+}
+
+func TestBuildTLSConfigWithClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateSelfSignedCert(t)
+
+	tlsConfig, err := buildTLSConfig(ClientConfig{
+		CABundle:      certPEM,
+		ClientCertPEM: certPEM,
+		ClientKeyPEM:  keyPEM,
+		ServerName:    "internal.example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("expected one client certificate to be configured, got %d", len(tlsConfig.Certificates))
+	}
+	if tlsConfig.RootCAs == nil {
+		t.Fatalf("expected RootCAs to be set from CABundle")
+	}
+	if tlsConfig.ServerName != "internal.example.com" {
+		t.Fatalf("expected ServerName to be propagated, got %q", tlsConfig.ServerName)
+	}
+}
+
+func TestBuildTLSConfigInvalidCABundle(t *testing.T) {
+	if _, err := buildTLSConfig(ClientConfig{CABundle: "not a pem bundle"}); err == nil {
+		t.Fatalf("expected an error for an invalid CA bundle")
+	}
+}
+
+func TestBuildTLSConfigMismatchedKeyPair(t *testing.T) {
+	certPEM, _ := generateSelfSignedCert(t)
+	_, otherKeyPEM := generateSelfSignedCert(t)
+
+	if _, err := buildTLSConfig(ClientConfig{
+		ClientCertPEM: certPEM,
+		ClientKeyPEM:  otherKeyPEM,
+	}); err == nil {
+		t.Fatalf("expected an error for a mismatched client certificate/key pair")
+	}
+}
+
+func TestClientWithTLSPropagatesContextOptions(t *testing.T) {
+	base := NewHTTP(nil, WithMaxBodyBytes(1024)).(*contextImpl)
+
+	result, err := base.ClientWithTLS(ClientConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tlsCtx := result.(*contextImpl)
+	if tlsCtx.maxBodyBytes != base.maxBodyBytes {
+		t.Fatalf("expected maxBodyBytes to carry over, got %d", tlsCtx.maxBodyBytes)
+	}
+	if tlsCtx.client == nil {
+		t.Fatalf("expected a configured client")
+	}
+}
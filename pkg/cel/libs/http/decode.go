@@ -0,0 +1,93 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Decoder turns a raw response body into a CEL-friendly value.
+type Decoder func(raw []byte) (any, error)
+
+// decoders maps a base media type (Content-Type without parameters, e.g.
+// "application/json") to the Decoder used to parse it. Types not covered
+// here are handled by decoderFor: "text/*" as a string, anything else via
+// decodeJSONThenBase64, which returns base64 under "body" when the body
+// isn't JSON.
+var decoders = map[string]Decoder{
+	"application/json":   decodeJSON,
+	"application/yaml":   decodeYAML,
+	"application/x-yaml": decodeYAML,
+	"text/yaml":          decodeYAML,
+}
+
+func decodeJSON(raw []byte) (any, error) {
+	var v any
+	if err := json.NewDecoder(bytes.NewReader(raw)).Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func decodeYAML(raw []byte) (any, error) {
+	var v any
+	if err := yaml.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func decodeText(raw []byte) (any, error) {
+	return string(raw), nil
+}
+
+func decodeBase64(raw []byte) (any, error) {
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// decoderFor picks the Decoder for a response based on its Content-Type
+// header, falling back to decodeText for any "text/*" type. When
+// Content-Type is absent or not one we recognize, it still tries JSON
+// first - many real endpoints (and most test servers) return JSON without
+// setting Content-Type at all - and only falls back to decodeBase64 (raw
+// bytes) if that fails, so no response body is ever silently dropped.
+func decoderFor(contentType string) Decoder {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if decoder, ok := decoders[mediaType]; ok {
+		return decoder
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return decodeText
+	}
+	return decodeJSONThenBase64
+}
+
+// decodeJSONThenBase64 is the fallback used for an absent or unrecognized
+// Content-Type: it preserves the pre-existing behavior of assuming JSON
+// before giving up and returning raw base64 bytes.
+func decodeJSONThenBase64(raw []byte) (any, error) {
+	if v, err := decodeJSON(raw); err == nil {
+		return v, nil
+	}
+	return decodeBase64(raw)
+}
+
+// drainBody reads resp.Body in full, honoring maxBodyBytes (0 means
+// unbounded) so a hostile or oversized endpoint can't exhaust memory.
+func drainBody(body io.ReadCloser, maxBodyBytes int64) ([]byte, error) {
+	var reader io.Reader = body
+	if maxBodyBytes > 0 {
+		reader = http.MaxBytesReader(nil, body, maxBodyBytes)
+	}
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestDecoderFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		raw         []byte
+		want        any
+	}{
+		{
+			name:        "application/json",
+			contentType: "application/json",
+			raw:         []byte(`{"a":1}`),
+			want:        map[string]any{"a": 1.0},
+		},
+		{
+			name:        "application/json with charset parameter",
+			contentType: "application/json; charset=utf-8",
+			raw:         []byte(`{"a":1}`),
+			want:        map[string]any{"a": 1.0},
+		},
+		{
+			name:        "application/yaml",
+			contentType: "application/yaml",
+			raw:         []byte("a: 1\n"),
+			want:        map[string]any{"a": 1.0},
+		},
+		{
+			name:        "text/plain",
+			contentType: "text/plain",
+			raw:         []byte("hello world"),
+			want:        "hello world",
+		},
+		{
+			name:        "missing content type falls back to JSON",
+			contentType: "",
+			raw:         []byte(`{"a":1}`),
+			want:        map[string]any{"a": 1.0},
+		},
+		{
+			name:        "unrecognized content type falls back to JSON",
+			contentType: "application/vnd.custom+thing",
+			raw:         []byte(`{"a":1}`),
+			want:        map[string]any{"a": 1.0},
+		},
+		{
+			name:        "unparseable body falls back to base64",
+			contentType: "application/octet-stream",
+			raw:         []byte{0x00, 0x01, 0x02, 0xff},
+			want:        base64.StdEncoding.EncodeToString([]byte{0x00, 0x01, 0x02, 0xff}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decoderFor(tt.contentType)(tt.raw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/kyverno/kyverno/pkg/tracing"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
@@ -19,16 +21,53 @@ type ClientInterface interface {
 }
 
 type contextImpl struct {
-	client ClientInterface
+	client       ClientInterface
+	retry        RetryPolicy
+	maxBodyBytes int64
+	auth         Authenticator
+	cache        *responseCache
+	protection   *hostProtector
 }
 
-func NewHTTP(client ClientInterface) ContextInterface {
+// Option customizes a contextImpl returned by NewHTTP or Client.
+type Option func(*contextImpl)
+
+// WithRetryPolicy overrides the retry behavior used by Get and Post.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *contextImpl) {
+		c.retry = policy
+	}
+}
+
+// WithMaxBodyBytes caps how many bytes of a response body are read before
+// decoding. A limit of 0 (the default) leaves the body unbounded.
+func WithMaxBodyBytes(n int64) Option {
+	return func(c *contextImpl) {
+		c.maxBodyBytes = n
+	}
+}
+
+// WithAuthenticator attaches credentials to every outgoing Get/Post request,
+// letting policies call Bearer/Basic/OAuth2/ServiceAccount-protected
+// endpoints without embedding long-lived secrets in the policy itself.
+func WithAuthenticator(auth Authenticator) Option {
+	return func(c *contextImpl) {
+		c.auth = auth
+	}
+}
+
+func NewHTTP(client ClientInterface, opts ...Option) ContextInterface {
 	if client == nil {
 		client = http.DefaultClient
 	}
-	return &contextImpl{
+	c := &contextImpl{
 		client: client,
+		retry:  DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // Get performs an HTTP GET request and returns the response.
@@ -43,20 +82,45 @@ func NewHTTP(client ClientInterface) ContextInterface {
 // Network errors (connection failures, timeouts) return an error and fail
 // policy evaluation. HTTP errors (4xx, 5xx) return the response object
 // with the corresponding status code, allowing policies to handle them.
+// Transient failures are retried according to the context's RetryPolicy.
+// Besides statusCode, the response also carries contentType, bodyRaw (the
+// base64 of the raw body) and, when the upstream sets it, retryAfter.
 //
 // Example usage in CEL:
 //   variables.response.statusCode == 200
 //   variables.response.statusCode == 404
 //   variables.response.data
 func (r *contextImpl) Get(url string, headers map[string]string) (any, error) {
-	req, err := http.NewRequestWithContext(context.TODO(), "GET", url, nil)
+	return r.GetWithContext(context.Background(), url, headers)
+}
+
+// GetWithContext is Get with an explicit context.Context for cancellation
+// and deadline propagation, for callers that aren't bound by the
+// ContextInterface signature Get implements for CEL registration.
+func (r *contextImpl) GetWithContext(ctx context.Context, url string, headers map[string]string) (any, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	// A nil-body request leaves GetBody unset, which the retry loop reads
+	// as "body can't be replayed" and bails out after the first attempt.
+	// GET has no body to replay, so always report one.
+	req.GetBody = func() (io.ReadCloser, error) {
+		return http.NoBody, nil
+	}
 	for h, v := range headers {
 		req.Header.Add(h, v)
 	}
-	return r.executeRequest(r.client, req)
+
+	if r.cache != nil && r.cache.cacheable(req.URL.Host) {
+		key := cacheKey("GET", url, headers, r.cache.cfg.HeaderKeys, nil)
+		return r.cache.do(key, func() (any, http.Header, error) {
+			result, respHeaders, err := r.executeRequestWithRetry(ctx, r.client, req)
+			return result, respHeaders, err
+		})
+	}
+	result, _, err := r.executeRequestWithRetry(ctx, r.client, req)
+	return result, err
 }
 
 // Post performs an HTTP POST request and returns the response.
@@ -71,40 +135,198 @@ func (r *contextImpl) Get(url string, headers map[string]string) (any, error) {
 // Network errors (connection failures, timeouts) return an error and fail
 // policy evaluation. HTTP errors (4xx, 5xx) return the response object
 // with the corresponding status code, allowing policies to handle them.
+// POST is only retried when the context's RetryPolicy explicitly opts in
+// via RetryPOST, since POST is not generally idempotent.
+// Besides statusCode, the response also carries contentType, bodyRaw (the
+// base64 of the raw body) and, when the upstream sets it, retryAfter.
 //
 // Example usage in CEL:
 //   variables.response.statusCode == 201
 //   variables.response.statusCode != 404
 //   variables.response.result
 func (r *contextImpl) Post(url string, data any, headers map[string]string) (any, error) {
-	body, err := buildRequestData(data)
+	return r.PostWithContext(context.Background(), url, data, headers)
+}
+
+// PostWithContext is Post with an explicit context.Context for cancellation
+// and deadline propagation, for callers that aren't bound by the
+// ContextInterface signature Post implements for CEL registration.
+func (r *contextImpl) PostWithContext(ctx context.Context, url string, data any, headers map[string]string) (any, error) {
+	bodyBytes, err := buildRequestData(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode request data: %w", err)
 	}
-	req, err := http.NewRequestWithContext(context.TODO(), "POST", url, body)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	// Allow the retry loop to rebuild the body on each attempt.
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(bodyBytes)), nil
+	}
 	for h, v := range headers {
 		req.Header.Add(h, v)
 	}
-	return r.executeRequest(r.client, req)
+
+	if r.cache != nil && r.cache.cacheable(req.URL.Host) {
+		key := cacheKey("POST", url, headers, r.cache.cfg.HeaderKeys, bodyBytes)
+		return r.cache.do(key, func() (any, http.Header, error) {
+			return r.executeRequestWithRetry(ctx, r.client, req)
+		})
+	}
+	result, _, err := r.executeRequestWithRetry(ctx, r.client, req)
+	return result, err
+}
+
+// executeRequestWithRetry runs req through executeRequest, retrying
+// according to r.retry when the attempt fails with a network error or a
+// retryable status code. The final attempt's response (success or not) is
+// always what's returned, so CEL policies see the last outcome. It also
+// returns the final attempt's response headers so callers (e.g. the
+// response cache) can honor upstream freshness hints.
+func (r *contextImpl) executeRequestWithRetry(ctx context.Context, client ClientInterface, req *http.Request) (any, http.Header, error) {
+	policy := r.retry
+
+	if policy.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.Deadline)
+		defer cancel()
+		req = req.Clone(ctx)
+	}
+
+	var (
+		result     any
+		respHeader http.Header
+		err        error
+	)
+	maxAttempts := policy.maxAttempts()
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			if req.GetBody == nil {
+				// Body already consumed and can't be replayed; stop retrying.
+				break
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return result, respHeader, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		attemptCtx := attemptReq.Context()
+		var cancel context.CancelFunc
+		if policy.PerAttemptTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(attemptCtx, policy.PerAttemptTimeout)
+			attemptReq = attemptReq.Clone(attemptCtx)
+		}
+
+		if r.auth != nil {
+			if authErr := r.auth.Authenticate(attemptCtx, attemptReq); authErr != nil {
+				if cancel != nil {
+					cancel()
+				}
+				return nil, nil, fmt.Errorf("failed to authenticate request: %w", authErr)
+			}
+		}
+
+		var guard *hostGuard
+		if r.protection != nil {
+			guard = r.protection.guardFor(attemptReq.URL.Host)
+			if allowErr := guard.allow(attemptCtx, attemptReq.URL.Host); allowErr != nil {
+				if cancel != nil {
+					cancel()
+				}
+				return breakerRejectionResponse(allowErr), nil, nil
+			}
+		}
+
+		result, respHeader, err = r.executeRequest(client, attemptReq)
+		if cancel != nil {
+			cancel()
+		}
+
+		if guard != nil {
+			statusCode, _ := statusCodeOf(result)
+			success := err == nil && statusCode < 500
+			guard.recordResult(attemptReq.URL.Host, success)
+		}
+
+		if !policy.retryableForMethod(attemptReq.Method) {
+			return result, respHeader, err
+		}
+		if attempt == maxAttempts-1 {
+			return result, respHeader, err
+		}
+
+		var retryAfter time.Duration
+		if err == nil {
+			statusCode, ok := statusCodeOf(result)
+			if !ok || !policy.isRetryableStatus(statusCode) {
+				return result, respHeader, err
+			}
+			if d, ok := retryAfterFromResult(result); ok {
+				retryAfter = d
+			}
+		}
+
+		delay := retryAfter
+		if delay == 0 {
+			delay = policy.backoff(attempt)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return result, respHeader, err
+		}
+	}
+	return result, respHeader, err
+}
+
+func statusCodeOf(result any) (int, bool) {
+	m, ok := result.(map[string]any)
+	if !ok {
+		return 0, false
+	}
+	code, ok := m["statusCode"].(int)
+	return code, ok
 }
 
-func (r *contextImpl) executeRequest(client ClientInterface, req *http.Request) (any, error) {
+func retryAfterFromResult(result any) (time.Duration, bool) {
+	m, ok := result.(map[string]any)
+	if !ok {
+		return 0, false
+	}
+	header, _ := m["retryAfter"].(string)
+	return parseRetryAfter(header)
+}
+
+func (r *contextImpl) executeRequest(client ClientInterface, req *http.Request) (any, http.Header, error) {
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Parse body regardless of status code
+	contentType := resp.Header.Get("Content-Type")
+
+	// Parse body regardless of status code. The body is decoded according
+	// to its Content-Type, falling back to raw base64 bytes so no body is
+	// ever silently dropped the way a JSON-only decode used to. bodyRaw
+	// keeps the base64 of the untouched bytes available even when decoding
+	// succeeds, so policies can fall back to it or verify the exact payload.
 	var body any
+	var bodyRaw string
 	if resp.Body != nil {
-		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
-			// If body parsing fails, set body to nil but continue
-			// This allows policies to check statusCode even when body is invalid
-			body = nil
+		raw, readErr := drainBody(resp.Body, r.maxBodyBytes)
+		if readErr == nil {
+			bodyRaw = base64.StdEncoding.EncodeToString(raw)
+			if decoded, decodeErr := decoderFor(contentType)(raw); decodeErr == nil {
+				body = decoded
+			}
+			// If decoding fails, body stays nil so policies can still check
+			// statusCode even when the body is invalid or unparseable.
 		}
 	}
 
@@ -112,42 +334,115 @@ func (r *contextImpl) executeRequest(client ClientInterface, req *http.Request)
 	// If body is a map, add statusCode to it (preserves existing field access)
 	if bodyMap, ok := body.(map[string]any); ok {
 		bodyMap["statusCode"] = resp.StatusCode
-		return bodyMap, nil
+		bodyMap["contentType"] = contentType
+		bodyMap["bodyRaw"] = bodyRaw
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			bodyMap["retryAfter"] = ra
+		}
+		return bodyMap, resp.Header, nil
 	}
 
 	// If body is not a map (array, primitive, or nil), wrap it
 	// This ensures statusCode is always accessible
-	return map[string]any{
-		"body":       body,
-		"statusCode": resp.StatusCode,
-	}, nil
+	result := map[string]any{
+		"body":        body,
+		"bodyRaw":     bodyRaw,
+		"statusCode":  resp.StatusCode,
+		"contentType": contentType,
+	}
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		result["retryAfter"] = ra
+	}
+	return result, resp.Header, nil
 }
 
 func (r *contextImpl) Client(caBundle string) (ContextInterface, error) {
 	if caBundle == "" {
 		return r, nil
 	}
-	caCertPool := x509.NewCertPool()
-	if ok := caCertPool.AppendCertsFromPEM([]byte(caBundle)); !ok {
-		return nil, fmt.Errorf("failed to parse PEM CA bundle for APICall")
+	return r.ClientWithTLS(ClientConfig{CABundle: caBundle})
+}
+
+// ClientConfig configures the TLS transport used by ClientWithTLS. CABundle
+// alone behaves like Client(caBundle); setting ClientCertPEM/ClientKeyPEM
+// additionally presents a client certificate for mutual TLS.
+type ClientConfig struct {
+	// CABundle is a PEM-encoded set of root CAs used to verify the server.
+	// Empty means use the system root CAs.
+	CABundle string
+
+	// ClientCertPEM and ClientKeyPEM are a PEM-encoded X.509 keypair
+	// presented to the server for mutual TLS. Both must be set together.
+	ClientCertPEM string
+	ClientKeyPEM  string
+
+	// ServerName overrides the SNI/verification hostname, for endpoints
+	// reached via an IP or a name that doesn't match the cert's SANs.
+	ServerName string
+
+	// InsecureSkipVerify disables server certificate verification. This
+	// should only ever be used against trusted test endpoints.
+	InsecureSkipVerify bool
+}
+
+// ClientWithTLS returns a ContextInterface backed by an HTTP client
+// configured with the given TLS settings, including optional mTLS client
+// certificates. This lets policy authors call APIs that require mutual TLS,
+// such as internal signing services or step-ca style PKIs, by referencing a
+// Secret containing cert+key+ca.
+func (r *contextImpl) ClientWithTLS(cfg ClientConfig) (ContextInterface, error) {
+	tlsConfig, err := buildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
 	}
+
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs:    caCertPool,
-			MinVersion: tls.VersionTLS12,
-		},
+		TLSClientConfig: tlsConfig,
 	}
 	return &contextImpl{
 		client: &http.Client{
 			Transport: tracing.Transport(transport, otelhttp.WithFilter(tracing.RequestFilterIsInSpan)),
 		},
+		retry:        r.retry,
+		maxBodyBytes: r.maxBodyBytes,
+		auth:         r.auth,
+		cache:        r.cache,
+		protection:   r.protection,
 	}, nil
 }
 
-func buildRequestData(data any) (io.Reader, error) {
+// buildTLSConfig translates a ClientConfig into a *tls.Config, parsing the
+// CA bundle and, if present, the mTLS client keypair.
+func buildTLSConfig(cfg ClientConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CABundle != "" {
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM([]byte(cfg.CABundle)); !ok {
+			return nil, fmt.Errorf("failed to parse PEM CA bundle for APICall")
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	if cfg.ClientCertPEM != "" || cfg.ClientKeyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(cfg.ClientCertPEM), []byte(cfg.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PEM client certificate/key for APICall: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func buildRequestData(data any) ([]byte, error) {
 	buffer := new(bytes.Buffer)
 	if err := json.NewEncoder(buffer).Encode(data); err != nil {
 		return nil, fmt.Errorf("failed to encode HTTP POST data %v: %w", data, err)
 	}
-	return buffer, nil
+	return buffer.Bytes(), nil
 }
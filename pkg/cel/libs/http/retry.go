@@ -0,0 +1,130 @@
+package http
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how executeRequest retries a failed or transient HTTP
+// call. The zero value disables retries (a single attempt is made).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts (including the first),
+	// so MaxAttempts <= 1 means no retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. Subsequent
+	// retries double this value up to MaxBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the computed backoff delay between attempts.
+	MaxBackoff time.Duration
+
+	// Jitter randomizes the computed backoff to avoid retry storms.
+	Jitter bool
+
+	// RetryableStatusCodes lists response status codes that should be
+	// retried. Network errors are always retryable.
+	RetryableStatusCodes []int
+
+	// PerAttemptTimeout bounds a single attempt, independent of the
+	// context passed in by the caller. Zero means no per-attempt timeout.
+	PerAttemptTimeout time.Duration
+
+	// Deadline bounds the entire sequence of attempts, including backoff
+	// waits. Zero means no overall deadline beyond the caller's context.
+	Deadline time.Duration
+
+	// RetryPOST allows POST requests to be retried. POST is not
+	// idempotent by default, so this must be explicitly opted into by
+	// callers that know the endpoint is safe to retry.
+	RetryPOST bool
+}
+
+// DefaultRetryPolicy returns a conservative policy: a single attempt, no
+// retries. Callers that want resilience against transient failures should
+// override it, e.g. via WithRetryPolicy.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    1,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Jitter:         true,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) isRetryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) retryableForMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete, http.MethodPut:
+		return true
+	case http.MethodPost:
+		return p.RetryPOST
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay to wait before the given retry attempt (0-based,
+// where 0 is the delay before the second overall attempt).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = 200 * time.Millisecond
+	}
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = 5 * time.Second
+	}
+	delay := initial << attempt
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+	if p.Jitter {
+		delay = time.Duration(rand.Int63n(int64(delay) + 1))
+	}
+	return delay
+}
+
+// parseRetryAfter parses the Retry-After header, which may be either a
+// number of seconds or an HTTP-date, per RFC 7231 7.1.3.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
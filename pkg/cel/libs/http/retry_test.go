@@ -0,0 +1,253 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// sequencedClient returns successive canned responses from responses on
+// each call to Do, so tests can assert on how many attempts were made.
+type sequencedClient struct {
+	responses []*http.Response
+	calls     int
+}
+
+func (c *sequencedClient) Do(req *http.Request) (*http.Response, error) {
+	resp := c.responses[c.calls]
+	c.calls++
+	return resp, nil
+}
+
+func jsonResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func retryAfterResponse(statusCode int, retryAfter string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header: http.Header{
+			"Content-Type": []string{"application/json"},
+			"Retry-After":  []string{retryAfter},
+		},
+		Body: io.NopCloser(bytes.NewReader([]byte(`{}`))),
+	}
+}
+
+// slowClient answers Do after delay, or as soon as the request's context is
+// canceled, so tests can exercise PerAttemptTimeout and Deadline.
+type slowClient struct {
+	delay time.Duration
+	calls int
+}
+
+func (c *slowClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	select {
+	case <-time.After(c.delay):
+		return jsonResponse(http.StatusOK, `{"ok":true}`), nil
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+}
+
+func TestGetRetriesPastRetryableStatus(t *testing.T) {
+	client := &sequencedClient{
+		responses: []*http.Response{
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+			jsonResponse(http.StatusTooManyRequests, `{}`),
+			jsonResponse(http.StatusOK, `{"ok":true}`),
+		},
+	}
+	ctx := NewHTTP(client, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests},
+	}))
+
+	result, err := ctx.Get("http://example.invalid", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", client.calls)
+	}
+	m, ok := result.(map[string]any)
+	if !ok {
+		t.Fatalf("expected map result, got %T", result)
+	}
+	if m["statusCode"] != http.StatusOK {
+		t.Fatalf("expected final statusCode 200, got %v", m["statusCode"])
+	}
+}
+
+func TestGetStopsAtMaxAttempts(t *testing.T) {
+	client := &sequencedClient{
+		responses: []*http.Response{
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+		},
+	}
+	ctx := NewHTTP(client, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          2,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}))
+
+	result, err := ctx.Get("http://example.invalid", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.calls != 2 {
+		t.Fatalf("expected exactly 2 attempts, got %d", client.calls)
+	}
+	m := result.(map[string]any)
+	if m["statusCode"] != http.StatusServiceUnavailable {
+		t.Fatalf("expected final statusCode 503, got %v", m["statusCode"])
+	}
+}
+
+// TestGetHonorsRetryAfterHeader sets a backoff large enough that the retry
+// would never land within the test's timeout if it fell back to it, so the
+// only way this passes quickly is if the Retry-After header is honored.
+func TestGetHonorsRetryAfterHeader(t *testing.T) {
+	client := &sequencedClient{
+		responses: []*http.Response{
+			retryAfterResponse(http.StatusServiceUnavailable, "1"),
+			jsonResponse(http.StatusOK, `{"ok":true}`),
+		},
+	}
+	ctx := NewHTTP(client, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          2,
+		InitialBackoff:       time.Hour,
+		MaxBackoff:           time.Hour,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}))
+
+	start := time.Now()
+	result, err := ctx.Get("http://example.invalid", nil)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed > 3*time.Second {
+		t.Fatalf("expected the Retry-After header to override the hour-long backoff, waited %v", elapsed)
+	}
+	m := result.(map[string]any)
+	if m["statusCode"] != http.StatusOK {
+		t.Fatalf("expected final statusCode 200, got %v", m["statusCode"])
+	}
+}
+
+// TestPostNotRetriedByDefault confirms POST is left alone unless the policy
+// opts in via RetryPOST, since POST is not generally idempotent.
+func TestPostNotRetriedByDefault(t *testing.T) {
+	client := &sequencedClient{
+		responses: []*http.Response{
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+		},
+	}
+	ctx := NewHTTP(client, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}))
+
+	result, err := ctx.Post("http://example.invalid", map[string]any{"a": 1}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.calls != 1 {
+		t.Fatalf("expected POST to not be retried without RetryPOST, got %d attempts", client.calls)
+	}
+	m := result.(map[string]any)
+	if m["statusCode"] != http.StatusServiceUnavailable {
+		t.Fatalf("expected final statusCode 503, got %v", m["statusCode"])
+	}
+}
+
+// TestPostRetriesWhenRetryPOSTEnabled confirms the opt-in gate works the
+// other way: once RetryPOST is set, POST is retried like any other method.
+func TestPostRetriesWhenRetryPOSTEnabled(t *testing.T) {
+	client := &sequencedClient{
+		responses: []*http.Response{
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+			jsonResponse(http.StatusOK, `{"ok":true}`),
+		},
+	}
+	ctx := NewHTTP(client, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          2,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		RetryPOST:            true,
+	}))
+
+	result, err := ctx.Post("http://example.invalid", map[string]any{"a": 1}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.calls != 2 {
+		t.Fatalf("expected POST to retry once RetryPOST is set, got %d attempts", client.calls)
+	}
+	m := result.(map[string]any)
+	if m["statusCode"] != http.StatusOK {
+		t.Fatalf("expected final statusCode 200, got %v", m["statusCode"])
+	}
+}
+
+// TestPerAttemptTimeoutAbortsSlowAttempt confirms PerAttemptTimeout cancels
+// an attempt that outlives it, independent of the caller's own context.
+func TestPerAttemptTimeoutAbortsSlowAttempt(t *testing.T) {
+	client := &slowClient{delay: 50 * time.Millisecond}
+	ctx := NewHTTP(client, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:       1,
+		PerAttemptTimeout: 10 * time.Millisecond,
+	}))
+
+	if _, err := ctx.Get("http://example.invalid", nil); err == nil {
+		t.Fatalf("expected PerAttemptTimeout to cancel a slow attempt")
+	}
+}
+
+// TestDeadlineBoundsRetrySequence confirms Deadline caps the whole retry
+// sequence, including backoff waits, rather than just the context the
+// caller passed in.
+func TestDeadlineBoundsRetrySequence(t *testing.T) {
+	client := &sequencedClient{
+		responses: []*http.Response{
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+			jsonResponse(http.StatusServiceUnavailable, `{}`),
+		},
+	}
+	ctx := NewHTTP(client, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          5,
+		InitialBackoff:       50 * time.Millisecond,
+		MaxBackoff:           50 * time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		Deadline:             60 * time.Millisecond,
+	}))
+
+	start := time.Now()
+	ctx.Get("http://example.invalid", nil)
+	elapsed := time.Since(start)
+
+	if elapsed > 500*time.Millisecond {
+		t.Fatalf("expected Deadline to bound the retry sequence, took %v", elapsed)
+	}
+	if client.calls >= 5 {
+		t.Fatalf("expected Deadline to stop retries before MaxAttempts, got %d attempts", client.calls)
+	}
+}